@@ -0,0 +1,200 @@
+package crop
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// BoundsCache is an LRU cache of detected crop bounds, keyed by a cheap
+// content hash of the source image. Batch workflows that re-crop
+// thousands of pages can skip the detector pass entirely on inputs they
+// have already seen, turning a full library re-run from minutes into
+// seconds.
+type BoundsCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	items    map[string]*list.Element
+}
+
+type boundsCacheEntry struct {
+	key    string
+	bounds image.Rectangle
+}
+
+// boundsCacheSnapshotEntry is the on-disk JSON representation of a single
+// cache entry. SaveFile/LoadFile use a slice of these, rather than a map,
+// so that recency order survives the round trip.
+type boundsCacheSnapshotEntry struct {
+	Key    string          `json:"key"`
+	Bounds image.Rectangle `json:"bounds"`
+}
+
+// NewBoundsCache returns an empty BoundsCache holding at most capacity
+// entries, evicting the least recently used entry once it is exceeded.
+func NewBoundsCache(capacity int) *BoundsCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &BoundsCache{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the cached bounds for key, if present, and marks it as
+// recently used.
+func (c *BoundsCache) Get(key string) (image.Rectangle, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return image.Rectangle{}, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*boundsCacheEntry).bounds, true
+}
+
+// Put stores bounds under key, evicting the least recently used entry if
+// the cache is over capacity.
+func (c *BoundsCache) Put(key string, bounds image.Rectangle) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		el.Value.(*boundsCacheEntry).bounds = bounds
+		c.order.MoveToFront(el)
+		return
+	}
+
+	el := c.order.PushFront(&boundsCacheEntry{key: key, bounds: bounds})
+	c.items[key] = el
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.items, oldest.Value.(*boundsCacheEntry).key)
+		}
+	}
+}
+
+// LoadFile merges a JSON snapshot previously written by SaveFile into the
+// cache, most-recently-used entries first, stopping once the cache
+// reaches capacity so the entries that survive are the ones that were
+// actually most recently used rather than whichever the snapshot
+// happened to list first. A missing file is not an error, so callers can
+// unconditionally load before a run and save after it.
+func (c *BoundsCache) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var entries []boundsCacheSnapshotEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var toLoad []boundsCacheSnapshotEntry
+	for _, e := range entries {
+		if _, ok := c.items[e.Key]; ok {
+			continue
+		}
+		if c.order.Len()+len(toLoad) >= c.capacity {
+			break
+		}
+		toLoad = append(toLoad, e)
+	}
+
+	// entries is most-recently-used first; pushing the least-recently-used
+	// of toLoad to the front first, and the most-recently-used last,
+	// reconstructs that same order at the front of c.order.
+	for i := len(toLoad) - 1; i >= 0; i-- {
+		e := toLoad[i]
+		el := c.order.PushFront(&boundsCacheEntry{key: e.Key, bounds: e.Bounds})
+		c.items[e.Key] = el
+	}
+
+	return nil
+}
+
+// SaveFile writes the current cache contents to path as JSON, most
+// recently used entry first, so a later LoadFile can truncate to
+// capacity without losing the entries most likely to be reused.
+func (c *BoundsCache) SaveFile(path string) error {
+	c.mu.Lock()
+	entries := make([]boundsCacheSnapshotEntry, 0, c.order.Len())
+	for el := c.order.Front(); el != nil; el = el.Next() {
+		entry := el.Value.(*boundsCacheEntry)
+		entries = append(entries, boundsCacheSnapshotEntry{Key: entry.key, Bounds: entry.bounds})
+	}
+	c.mu.Unlock()
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// HashImage returns a cheap content hash of img, suitable as a
+// BoundsCache key. It hashes the image's dimensions plus its first and
+// last rows rather than every pixel, which is enough to distinguish
+// unrelated pages while staying fast on large libraries.
+func HashImage(img image.Image) string {
+	bounds := img.Bounds()
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%dx%d", bounds.Dx(), bounds.Dy())
+
+	writeRow := func(y int) {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.GrayModel.Convert(img.At(x, y)).(color.Gray)
+			h.Write([]byte{c.Y})
+		}
+	}
+
+	if bounds.Dy() > 0 {
+		writeRow(bounds.Min.Y)
+		writeRow(bounds.Max.Y - 1)
+	}
+
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// AutoCached is a thin wrapper around AutoCachedOptions using
+// DefaultOptions.
+func AutoCached(img image.Image, cache *BoundsCache) (image.Image, error) {
+	return AutoCachedOptions(img, cache, DefaultOptions())
+}
+
+// AutoCachedOptions crops img using AvgHashDetector tuned by opts,
+// skipping detection in favor of a cached result when img's content hash
+// has already been seen.
+func AutoCachedOptions(img image.Image, cache *BoundsCache, opts Options) (image.Image, error) {
+	key := HashImage(img)
+
+	bounds, ok := cache.Get(key)
+	if !ok {
+		bounds = AvgHashDetector{Options: opts}.DetectBounds(img)
+		cache.Put(key, bounds)
+	}
+
+	return Crop(img, bounds)
+}
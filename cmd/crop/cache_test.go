@@ -0,0 +1,123 @@
+package crop
+
+import (
+	"encoding/json"
+	"image"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBoundsCacheGetPut(t *testing.T) {
+	cache := NewBoundsCache(2)
+	want := image.Rect(1, 2, 3, 4)
+
+	if _, ok := cache.Get("a"); ok {
+		t.Fatalf("Get(a) on empty cache = ok, want miss")
+	}
+
+	cache.Put("a", want)
+	got, ok := cache.Get("a")
+	if !ok || got != want {
+		t.Errorf("Get(a) = (%v, %v), want (%v, true)", got, ok, want)
+	}
+}
+
+func TestBoundsCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewBoundsCache(2)
+	cache.Put("a", image.Rect(0, 0, 1, 1))
+	cache.Put("b", image.Rect(0, 0, 2, 2))
+
+	// Touch "a" so "b" becomes the least recently used entry.
+	cache.Get("a")
+	cache.Put("c", image.Rect(0, 0, 3, 3))
+
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("Get(b) after eviction = ok, want miss")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Errorf("Get(a) after eviction = miss, want a hit")
+	}
+	if _, ok := cache.Get("c"); !ok {
+		t.Errorf("Get(c) after eviction = miss, want a hit")
+	}
+}
+
+func TestBoundsCacheLoadFileRespectsCapacity(t *testing.T) {
+	snapshot := []boundsCacheSnapshotEntry{
+		{Key: "a", Bounds: image.Rect(0, 0, 1, 1)},
+		{Key: "b", Bounds: image.Rect(0, 0, 2, 2)},
+		{Key: "c", Bounds: image.Rect(0, 0, 3, 3)},
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "bounds.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	cache := NewBoundsCache(2)
+	if err := cache.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+
+	if cache.order.Len() > 2 {
+		t.Errorf("cache has %d entries after LoadFile, want at most capacity 2", cache.order.Len())
+	}
+}
+
+func TestBoundsCacheSaveFileLoadFileRoundTrip(t *testing.T) {
+	cache := NewBoundsCache(4)
+	cache.Put("a", image.Rect(0, 0, 5, 6))
+
+	path := filepath.Join(t.TempDir(), "bounds.json")
+	if err := cache.SaveFile(path); err != nil {
+		t.Fatalf("SaveFile() error = %v", err)
+	}
+
+	loaded := NewBoundsCache(4)
+	if err := loaded.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+
+	got, ok := loaded.Get("a")
+	want := image.Rect(0, 0, 5, 6)
+	if !ok || got != want {
+		t.Errorf("Get(a) after round trip = (%v, %v), want (%v, true)", got, ok, want)
+	}
+}
+
+func TestBoundsCacheSaveFileLoadFileKeepsMostRecentlyUsedOverCapacity(t *testing.T) {
+	cache := NewBoundsCache(10)
+	keys := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+	for i, key := range keys {
+		cache.Put(key, image.Rect(0, 0, i+1, i+1))
+	}
+
+	// "a" was the first entry put in, so it is the least recently used
+	// until this Get marks it most recently used instead.
+	cache.Get("a")
+
+	path := filepath.Join(t.TempDir(), "bounds.json")
+	if err := cache.SaveFile(path); err != nil {
+		t.Fatalf("SaveFile() error = %v", err)
+	}
+
+	loaded := NewBoundsCache(3)
+	if err := loaded.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+
+	if loaded.order.Len() != 3 {
+		t.Fatalf("cache has %d entries after LoadFile, want 3", loaded.order.Len())
+	}
+	if _, ok := loaded.Get("a"); !ok {
+		t.Errorf(`Get("a") after capacity-3 reload = miss, want a hit since it was most recently used`)
+	}
+	if _, ok := loaded.Get("b"); ok {
+		t.Errorf(`Get("b") after capacity-3 reload = hit, want a miss since it was never re-touched`)
+	}
+}
@@ -0,0 +1,184 @@
+package crop
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Denoise selects an optional preprocessing filter applied to a grayscale
+// copy of the image before border hashing, to keep scanner speckle and
+// JPEG mosquito noise from halting the inward scan early.
+type Denoise int
+
+const (
+	// DenoiseNone runs border hashing directly on the source image.
+	DenoiseNone Denoise = iota
+	// DenoiseMedian3 applies a 3x3 median filter.
+	DenoiseMedian3
+	// DenoiseMedian5 applies a 5x5 median filter.
+	DenoiseMedian5
+	// DenoiseGaussian applies a small Gaussian blur (sigma ~= 1.0).
+	DenoiseGaussian
+)
+
+// gaussianSigma is the standard deviation used for DenoiseGaussian.
+const gaussianSigma = 1.0
+
+// denoise returns a grayscale copy of img with the requested filter
+// applied, or a plain grayscale copy if mode is DenoiseNone.
+func denoise(img image.Image, mode Denoise) *image.Gray {
+	gray := toGray(img)
+	switch mode {
+	case DenoiseMedian3:
+		return medianFilter(gray, 3)
+	case DenoiseMedian5:
+		return medianFilter(gray, 5)
+	case DenoiseGaussian:
+		return gaussianBlur(gray, gaussianSigma)
+	default:
+		return gray
+	}
+}
+
+// toGray converts img to grayscale, preserving its bounds.
+func toGray(img image.Image) *image.Gray {
+	bounds := img.Bounds()
+	gray := image.NewGray(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.GrayModel.Convert(img.At(x, y)).(color.Gray)
+			gray.SetGray(x, y, c)
+		}
+	}
+	return gray
+}
+
+// medianFilter replaces each pixel with the median of the size*size window
+// centered on it, found via partial-sort (quickselect) rather than a full
+// sort of the window.
+func medianFilter(gray *image.Gray, size int) *image.Gray {
+	bounds := gray.Bounds()
+	out := image.NewGray(bounds)
+	half := size / 2
+	window := make([]uint8, 0, size*size)
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			window = window[:0]
+			for wy := y - half; wy <= y+half; wy++ {
+				if wy < bounds.Min.Y || wy >= bounds.Max.Y {
+					continue
+				}
+				for wx := x - half; wx <= x+half; wx++ {
+					if wx < bounds.Min.X || wx >= bounds.Max.X {
+						continue
+					}
+					window = append(window, gray.GrayAt(wx, wy).Y)
+				}
+			}
+			out.SetGray(x, y, color.Gray{Y: quickselectMedian(window)})
+		}
+	}
+	return out
+}
+
+// quickselectMedian returns the median of values using Hoare partitioning,
+// without fully sorting the slice. values is modified in place.
+func quickselectMedian(values []uint8) uint8 {
+	k := len(values) / 2
+	lo, hi := 0, len(values)-1
+	for lo < hi {
+		pivot := values[(lo+hi)/2]
+		i, j := lo, hi
+		for i <= j {
+			for values[i] < pivot {
+				i++
+			}
+			for values[j] > pivot {
+				j--
+			}
+			if i <= j {
+				values[i], values[j] = values[j], values[i]
+				i++
+				j--
+			}
+		}
+		if k <= j {
+			hi = j
+		} else if k >= i {
+			lo = i
+		} else {
+			break
+		}
+	}
+	return values[k]
+}
+
+// gaussianBlur applies a separable 1-D Gaussian convolution, first across
+// rows and then down columns, using a kernel precomputed for sigma.
+func gaussianBlur(gray *image.Gray, sigma float64) *image.Gray {
+	kernel := gaussianKernel(sigma)
+	half := len(kernel) / 2
+
+	bounds := gray.Bounds()
+	horizontal := image.NewGray(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			var sum float64
+			for k, weight := range kernel {
+				sx := x + k - half
+				if sx < bounds.Min.X {
+					sx = bounds.Min.X
+				} else if sx >= bounds.Max.X {
+					sx = bounds.Max.X - 1
+				}
+				sum += weight * float64(gray.GrayAt(sx, y).Y)
+			}
+			horizontal.SetGray(x, y, color.Gray{Y: uint8(sum + 0.5)})
+		}
+	}
+
+	out := image.NewGray(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			var sum float64
+			for k, weight := range kernel {
+				sy := y + k - half
+				if sy < bounds.Min.Y {
+					sy = bounds.Min.Y
+				} else if sy >= bounds.Max.Y {
+					sy = bounds.Max.Y - 1
+				}
+				sum += weight * float64(horizontal.GrayAt(x, sy).Y)
+			}
+			out.SetGray(x, y, color.Gray{Y: uint8(sum + 0.5)})
+		}
+	}
+
+	return out
+}
+
+// gaussianKernel builds a normalized 1-D kernel covering +/-3 sigma.
+func gaussianKernel(sigma float64) []float64 {
+	radius := int(sigma*3 + 0.5)
+	if radius < 1 {
+		radius = 1
+	}
+	kernel := make([]float64, 2*radius+1)
+	var sum float64
+	for i := range kernel {
+		d := float64(i - radius)
+		v := gaussianWeight(d, sigma)
+		kernel[i] = v
+		sum += v
+	}
+	for i := range kernel {
+		kernel[i] /= sum
+	}
+	return kernel
+}
+
+func gaussianWeight(d, sigma float64) float64 {
+	return math.Exp(-(d * d) / (2 * sigma * sigma))
+}
@@ -0,0 +1,60 @@
+package crop
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestQuickselectMedianOddLength(t *testing.T) {
+	values := []uint8{5, 1, 4, 2, 8}
+	if got := quickselectMedian(values); got != 4 {
+		t.Errorf("quickselectMedian(%v) = %d, want 4", []uint8{5, 1, 4, 2, 8}, got)
+	}
+}
+
+func TestQuickselectMedianEvenLength(t *testing.T) {
+	values := []uint8{4, 1, 3, 2}
+	if got := quickselectMedian(values); got != 3 {
+		t.Errorf("quickselectMedian(%v) = %d, want 3 (upper median)", []uint8{4, 1, 3, 2}, got)
+	}
+}
+
+func TestMedianFilter3x3SmoothsOutASingleSpeck(t *testing.T) {
+	gray := image.NewGray(image.Rect(0, 0, 5, 5))
+	for y := 0; y < 5; y++ {
+		for x := 0; x < 5; x++ {
+			gray.SetGray(x, y, color.Gray{Y: 10})
+		}
+	}
+	gray.SetGray(2, 2, color.Gray{Y: 255})
+
+	out := medianFilter(gray, 3)
+
+	for y := 0; y < 5; y++ {
+		for x := 0; x < 5; x++ {
+			if got := out.GrayAt(x, y).Y; got != 10 {
+				t.Errorf("medianFilter(speck)[%d][%d] = %d, want 10 (speck outvoted by the flat background)", x, y, got)
+			}
+		}
+	}
+}
+
+func TestGaussianBlurLeavesFlatRegionUnchanged(t *testing.T) {
+	gray := image.NewGray(image.Rect(0, 0, 7, 7))
+	for y := 0; y < 7; y++ {
+		for x := 0; x < 7; x++ {
+			gray.SetGray(x, y, color.Gray{Y: 100})
+		}
+	}
+
+	out := gaussianBlur(gray, gaussianSigma)
+
+	for y := 0; y < 7; y++ {
+		for x := 0; x < 7; x++ {
+			if got := out.GrayAt(x, y).Y; got != 100 {
+				t.Errorf("gaussianBlur(flat)[%d][%d] = %d, want 100", x, y, got)
+			}
+		}
+	}
+}
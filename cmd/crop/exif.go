@@ -0,0 +1,225 @@
+package crop
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+)
+
+// exifOrientationTag is the EXIF tag ID for the Orientation field.
+const exifOrientationTag = 0x0112
+
+// AutoOriented decodes an image from r, applies any EXIF orientation
+// correction found in it, and then runs the default avg-hash border
+// detection on the upright result. Scanlated pages occasionally carry an
+// EXIF orientation tag from phone-captured raws or re-encodes; without
+// correcting for it, Auto would compute a bounding box in the wrong
+// pixel grid and downstream code would show the page sideways.
+func AutoOriented(r io.Reader) (image.Image, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	oriented := ApplyOrientation(img, exifOrientation(data))
+
+	cropped, err := Auto(oriented)
+	if err != nil {
+		return nil, err
+	}
+
+	return cropped, nil
+}
+
+// ApplyOrientation rotates and/or flips img according to an EXIF
+// Orientation value (1-8, per the TIFF/EXIF specification). Orientation 1
+// (the default) and values outside that range return img unchanged.
+func ApplyOrientation(img image.Image, orientation int) image.Image {
+	switch orientation {
+	case 2:
+		return flipHorizontal(img)
+	case 3:
+		return rotate180(img)
+	case 4:
+		return flipVertical(img)
+	case 5:
+		return transpose(img)
+	case 6:
+		return rotate90CW(img)
+	case 7:
+		return transverse(img)
+	case 8:
+		return rotate270CW(img)
+	default:
+		return img
+	}
+}
+
+func rotate90CW(img image.Image) image.Image {
+	b := img.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, b.Dy(), b.Dx()))
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			out.Set(b.Max.Y-1-y+b.Min.Y, x-b.Min.X, img.At(x, y))
+		}
+	}
+	return out
+}
+
+func rotate270CW(img image.Image) image.Image {
+	b := img.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, b.Dy(), b.Dx()))
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			out.Set(y-b.Min.Y, b.Max.X-1-x+b.Min.X, img.At(x, y))
+		}
+	}
+	return out
+}
+
+func rotate180(img image.Image) image.Image {
+	b := img.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			out.Set(b.Max.X-1-x+b.Min.X, b.Max.Y-1-y+b.Min.Y, img.At(x, y))
+		}
+	}
+	return out
+}
+
+func flipHorizontal(img image.Image) image.Image {
+	b := img.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			out.Set(b.Max.X-1-x+b.Min.X, y-b.Min.Y, img.At(x, y))
+		}
+	}
+	return out
+}
+
+func flipVertical(img image.Image) image.Image {
+	b := img.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			out.Set(x-b.Min.X, b.Max.Y-1-y+b.Min.Y, img.At(x, y))
+		}
+	}
+	return out
+}
+
+// transpose mirrors img across its top-left/bottom-right diagonal.
+func transpose(img image.Image) image.Image {
+	b := img.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, b.Dy(), b.Dx()))
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			out.Set(y-b.Min.Y, x-b.Min.X, img.At(x, y))
+		}
+	}
+	return out
+}
+
+// transverse mirrors img across its top-right/bottom-left diagonal.
+func transverse(img image.Image) image.Image {
+	b := img.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, b.Dy(), b.Dx()))
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			out.Set(b.Max.Y-1-y+b.Min.Y, b.Max.X-1-x+b.Min.X, img.At(x, y))
+		}
+	}
+	return out
+}
+
+// exifOrientation scans a JPEG byte stream for an APP1 EXIF segment and
+// returns its Orientation tag, defaulting to 1 (no correction needed) if
+// the segment, tag, or a well-formed TIFF header cannot be found.
+func exifOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			break
+		}
+		marker := data[pos+1]
+		if marker == 0xD8 || marker == 0xD9 {
+			pos += 2
+			continue
+		}
+		if marker < 0xD0 || marker > 0xD9 {
+			segmentLen := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+			if segmentLen < 2 {
+				break
+			}
+			segment := data[pos+4 : min(pos+2+segmentLen, len(data))]
+			if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+				if o, ok := parseExifOrientation(segment[6:]); ok {
+					return o
+				}
+			}
+			pos += 2 + segmentLen
+			continue
+		}
+		pos += 2
+	}
+
+	return 1
+}
+
+func parseExifOrientation(tiff []byte) (int, bool) {
+	if len(tiff) < 8 {
+		return 0, false
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[0:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0, false
+	}
+
+	ifdOffset := order.Uint32(tiff[4:8])
+	if int(ifdOffset)+2 > len(tiff) {
+		return 0, false
+	}
+
+	count := int(order.Uint16(tiff[ifdOffset : ifdOffset+2]))
+	entriesStart := int(ifdOffset) + 2
+	for i := 0; i < count; i++ {
+		entryStart := entriesStart + i*12
+		if entryStart+12 > len(tiff) {
+			break
+		}
+		entry := tiff[entryStart : entryStart+12]
+		tag := order.Uint16(entry[0:2])
+		if tag == exifOrientationTag {
+			return int(order.Uint16(entry[8:10])), true
+		}
+	}
+
+	return 0, false
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
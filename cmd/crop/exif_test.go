@@ -0,0 +1,81 @@
+package crop
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"testing"
+)
+
+// buildJPEGWithOrientation assembles a minimal JPEG byte stream carrying a
+// single EXIF Orientation tag in its APP1 segment.
+func buildJPEGWithOrientation(t *testing.T, orientation uint16) []byte {
+	t.Helper()
+
+	var tiff bytes.Buffer
+	tiff.WriteString("II")
+	binary.Write(&tiff, binary.LittleEndian, uint16(0x2A))
+	binary.Write(&tiff, binary.LittleEndian, uint32(8)) // offset to IFD
+	binary.Write(&tiff, binary.LittleEndian, uint16(1)) // one entry
+	binary.Write(&tiff, binary.LittleEndian, uint16(exifOrientationTag))
+	binary.Write(&tiff, binary.LittleEndian, uint16(3)) // type SHORT
+	binary.Write(&tiff, binary.LittleEndian, uint32(1)) // count
+	binary.Write(&tiff, binary.LittleEndian, orientation)
+	binary.Write(&tiff, binary.LittleEndian, uint16(0)) // value field padding
+	binary.Write(&tiff, binary.LittleEndian, uint32(0)) // next IFD offset
+
+	var segment bytes.Buffer
+	segment.WriteString("Exif\x00\x00")
+	segment.Write(tiff.Bytes())
+
+	var data bytes.Buffer
+	data.Write([]byte{0xFF, 0xD8})
+	data.Write([]byte{0xFF, 0xE1})
+	binary.Write(&data, binary.BigEndian, uint16(segment.Len()+2))
+	data.Write(segment.Bytes())
+	data.Write([]byte{0xFF, 0xD9})
+
+	return data.Bytes()
+}
+
+func TestExifOrientationReadsTag(t *testing.T) {
+	data := buildJPEGWithOrientation(t, 6)
+	if got := exifOrientation(data); got != 6 {
+		t.Errorf("exifOrientation() = %d, want 6", got)
+	}
+}
+
+func TestExifOrientationDefaultsWithoutTag(t *testing.T) {
+	data := []byte{0xFF, 0xD8, 0xFF, 0xD9}
+	if got := exifOrientation(data); got != 1 {
+		t.Errorf("exifOrientation(no APP1) = %d, want 1", got)
+	}
+}
+
+func TestExifOrientationDoesNotPanicOnMalformedSegmentLength(t *testing.T) {
+	data := []byte{0xFF, 0xD8, 0xFF, 0xE1, 0x00, 0x00, 0xFF, 0xD9}
+	if got := exifOrientation(data); got != 1 {
+		t.Errorf("exifOrientation(malformed segment) = %d, want 1", got)
+	}
+}
+
+func TestApplyOrientationRotate90CWSwapsDimensions(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+
+	out := ApplyOrientation(src, 6)
+
+	b := out.Bounds()
+	if b.Dx() != 2 || b.Dy() != 3 {
+		t.Errorf("ApplyOrientation(orientation 6).Bounds() = %v, want a 2x3 image", b)
+	}
+}
+
+func TestApplyOrientationDefaultReturnsUnchanged(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+
+	out := ApplyOrientation(src, 1)
+
+	if out.Bounds() != src.Bounds() {
+		t.Errorf("ApplyOrientation(orientation 1).Bounds() = %v, want %v", out.Bounds(), src.Bounds())
+	}
+}
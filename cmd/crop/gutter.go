@@ -0,0 +1,165 @@
+package crop
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+)
+
+// gutterBandStart and gutterBandEnd bound the central fraction of a
+// cropped page's width that is searched for a spread gutter.
+const gutterBandStart = 0.30
+const gutterBandEnd = 0.70
+
+// gutterMaxDensityOfMedian is the largest ink density, as a fraction of
+// the page's median column density, a column may have and still be
+// considered part of the gutter.
+const gutterMaxDensityOfMedian = 0.15
+
+// gutterMinRunWidth is the minimum width, in pixels, of the contiguous
+// low-density run the candidate gutter column must sit within.
+const gutterMinRunWidth = 5
+
+// DetectGutter is a thin wrapper around DetectGutterOptions using
+// DefaultOptions.
+func DetectGutter(img image.Image) (splitX int, ok bool) {
+	return DetectGutterOptions(img, DefaultOptions())
+}
+
+// DetectGutterOptions searches a border-trimmed double-page spread for
+// the vertical gutter between its two pages. It builds a per-column ink
+// density profile from an integral image keyed off
+// opts.GrayDarknessLimit (so each column's darkness is an O(1) lookup
+// after the O(W*H) build), then looks within the central 30%-70% band of
+// the width for the column with the lowest density. The candidate must
+// fall well below the page's median column density and sit within a
+// wide enough contiguous low-density run to rule out a stray gap between
+// panels; otherwise ok is false.
+func DetectGutterOptions(img image.Image, opts Options) (splitX int, ok bool) {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 {
+		return 0, false
+	}
+
+	integral := buildColumnInkIntegral(img, opts)
+	density := make([]float64, w)
+	for x := 0; x < w; x++ {
+		dark := integral[x+1] - integral[x]
+		density[x] = float64(dark) / float64(h)
+	}
+
+	median := medianFloat(density)
+	if median == 0 {
+		return 0, false
+	}
+
+	bandStart := int(float64(w) * gutterBandStart)
+	bandEnd := int(float64(w) * gutterBandEnd)
+
+	best := -1
+	bestDensity := math.Inf(1)
+	for x := bandStart; x < bandEnd; x++ {
+		if density[x] < bestDensity {
+			bestDensity = density[x]
+			best = x
+		}
+	}
+
+	threshold := median * gutterMaxDensityOfMedian
+	if best < 0 || bestDensity > threshold {
+		return 0, false
+	}
+
+	if lowDensityRunWidth(density, best, threshold) < gutterMinRunWidth {
+		return 0, false
+	}
+
+	return best + bounds.Min.X, true
+}
+
+// AutoSplit is a thin wrapper around AutoSplitOptions using
+// DefaultOptions.
+func AutoSplit(img image.Image) ([]image.Image, error) {
+	return AutoSplitOptions(img, DefaultOptions())
+}
+
+// AutoSplitOptions crops img with AvgHashDetector tuned by opts and, if a
+// gutter is found, splits the result into left and right page images.
+// Pages without a detectable gutter are returned as a single-element
+// slice, so mixed single/spread chapters can be processed without
+// per-file intervention.
+func AutoSplitOptions(img image.Image, opts Options) ([]image.Image, error) {
+	cropped, err := Crop(img, AvgHashDetector{Options: opts}.DetectBounds(img))
+	if err != nil {
+		return nil, err
+	}
+
+	splitX, ok := DetectGutterOptions(cropped, opts)
+	if !ok {
+		return []image.Image{cropped}, nil
+	}
+
+	bounds := cropped.Bounds()
+	left, err := Crop(cropped, image.Rect(bounds.Min.X, bounds.Min.Y, splitX, bounds.Max.Y))
+	if err != nil {
+		return nil, err
+	}
+	right, err := Crop(cropped, image.Rect(splitX, bounds.Min.Y, bounds.Max.X, bounds.Max.Y))
+	if err != nil {
+		return nil, err
+	}
+
+	return []image.Image{left, right}, nil
+}
+
+// buildColumnInkIntegral returns a length-(w+1) prefix sum of dark pixel
+// counts per column, so the ink count of any column range is a subtract
+// of two entries. A pixel counts as ink if it is at or below
+// opts.GrayDarknessLimit.
+func buildColumnInkIntegral(img image.Image, opts Options) []int64 {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	integral := make([]int64, w+1)
+
+	for x := 0; x < w; x++ {
+		var dark int64
+		for y := 0; y < h; y++ {
+			c := color.GrayModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.Gray)
+			if c.Y <= opts.GrayDarknessLimit {
+				dark++
+			}
+		}
+		integral[x+1] = integral[x] + dark
+	}
+
+	return integral
+}
+
+// lowDensityRunWidth returns the width of the contiguous run of columns
+// around center whose density does not exceed threshold.
+func lowDensityRunWidth(density []float64, center int, threshold float64) int {
+	left := center
+	for left > 0 && density[left-1] <= threshold {
+		left--
+	}
+	right := center
+	for right < len(density)-1 && density[right+1] <= threshold {
+		right++
+	}
+	return right - left + 1
+}
+
+func medianFloat(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
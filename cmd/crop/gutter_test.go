@@ -0,0 +1,97 @@
+package crop
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// buildSpreadImage returns a w x h grayscale image that is entirely dark
+// ("ink") except for a white vertical band [gutterStart, gutterStart+gutterWidth)
+// standing in for the gutter between two pages.
+func buildSpreadImage(w, h, gutterStart, gutterWidth int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := uint8(0)
+			if x >= gutterStart && x < gutterStart+gutterWidth {
+				v = 255
+			}
+			img.SetGray(x, y, color.Gray{Y: v})
+		}
+	}
+	return img
+}
+
+func TestDetectGutterFindsCleanSpread(t *testing.T) {
+	img := buildSpreadImage(100, 40, 45, 11)
+
+	splitX, ok := DetectGutter(img)
+	if !ok {
+		t.Fatalf("DetectGutter(clean spread) ok = false, want true")
+	}
+	if splitX < 45 || splitX > 55 {
+		t.Errorf("DetectGutter(clean spread) splitX = %d, want in [45, 55]", splitX)
+	}
+}
+
+func TestDetectGutterNoGutterOnSinglePage(t *testing.T) {
+	img := buildSpreadImage(100, 40, 0, 0) // entirely dark, no white band at all
+
+	if _, ok := DetectGutter(img); ok {
+		t.Errorf("DetectGutter(single page) ok = true, want false")
+	}
+}
+
+func TestDetectGutterRejectsRunNarrowerThanMinWidth(t *testing.T) {
+	img := buildSpreadImage(100, 40, 48, gutterMinRunWidth-2)
+
+	if _, ok := DetectGutter(img); ok {
+		t.Errorf("DetectGutter(narrow gap) ok = true, want false since the run is narrower than gutterMinRunWidth")
+	}
+}
+
+func TestAutoSplitOptionsSplitsCleanSpread(t *testing.T) {
+	img := buildSpreadImage(100, 40, 45, 11)
+
+	// Thresholds tuned so AvgHashDetector never fires, leaving the crop
+	// step a no-op and keeping the split coordinates predictable.
+	opts := DefaultOptions()
+	opts.MinHammingDistance = 1000
+	opts.MaxHammingDistance = 1000
+
+	pages, err := AutoSplitOptions(img, opts)
+	if err != nil {
+		t.Fatalf("AutoSplitOptions() error = %v", err)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("AutoSplitOptions(clean spread) returned %d image(s), want 2", len(pages))
+	}
+
+	left, right := pages[0].Bounds(), pages[1].Bounds()
+	if left.Max.X != right.Min.X {
+		t.Errorf("left.Max.X = %d, right.Min.X = %d, want the split to be contiguous", left.Max.X, right.Min.X)
+	}
+	if left.Min.X != 0 || right.Max.X != 100 {
+		t.Errorf("pages = %v / %v, want to span the full [0, 100) width between them", left, right)
+	}
+	if left.Min.Y != 0 || left.Max.Y != 40 || right.Min.Y != 0 || right.Max.Y != 40 {
+		t.Errorf("pages = %v / %v, want both to retain the full [0, 40) height", left, right)
+	}
+}
+
+func TestAutoSplitOptionsReturnsSinglePageWithoutGutter(t *testing.T) {
+	img := buildSpreadImage(100, 40, 0, 0)
+
+	opts := DefaultOptions()
+	opts.MinHammingDistance = 1000
+	opts.MaxHammingDistance = 1000
+
+	pages, err := AutoSplitOptions(img, opts)
+	if err != nil {
+		t.Fatalf("AutoSplitOptions() error = %v", err)
+	}
+	if len(pages) != 1 {
+		t.Fatalf("AutoSplitOptions(single page) returned %d image(s), want 1", len(pages))
+	}
+}
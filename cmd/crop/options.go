@@ -0,0 +1,145 @@
+package crop
+
+import "image"
+
+// Detector computes the bounding box of the page content within img,
+// discarding any surrounding whitespace or border. Implementations are
+// free to use whatever heuristic suits the kind of scan they target.
+type Detector interface {
+	DetectBounds(img image.Image) image.Rectangle
+}
+
+// Padding adds a fixed margin, in pixels, back onto each edge of a
+// detected crop so that content is not shaved too close.
+type Padding struct {
+	Left, Right, Top, Bottom int
+}
+
+// Options tunes the built-in detectors. Zero-value Options is not
+// meaningful; start from DefaultOptions and override individual fields.
+type Options struct {
+	// GrayDarknessLimit is the grayscale value at or below which a pixel
+	// is considered part of the page content rather than whitespace.
+	GrayDarknessLimit uint8
+	// MinHammingDistance is the minimum Hamming distance between
+	// consecutive line hashes required to mark a border when the previous
+	// line was uniformly black or white.
+	MinHammingDistance int
+	// MaxHammingDistance is the general-case minimum Hamming distance
+	// between consecutive line hashes required to mark a border.
+	MaxHammingDistance int
+	// HighContrastPercent is the percentage of a block window's pixels that
+	// must be near-black (in a light window) or near-white (in a dark
+	// window) for that window's bit in the line's high-contrast hash to be
+	// set.
+	HighContrastPercent float64
+	// BlackThreshold is the grayscale value below which a pixel counts
+	// towards its block window's high-contrast black count.
+	BlackThreshold uint8
+	// WhiteThreshold is the grayscale value above which a pixel counts
+	// towards its block window's high-contrast white count.
+	WhiteThreshold uint8
+	// Padding is added back onto each edge of the detected bounds.
+	Padding Padding
+	// MinRetainedFraction is the smallest area, as a fraction of the
+	// original image area, that a crop may retain. Crops that would retain
+	// less than this are refused and the full image bounds are returned
+	// instead. Zero disables the check.
+	MinRetainedFraction float64
+	// Denoise selects a filter run over a grayscale copy of the image
+	// before AvgHashDetector hashes it, to suppress scanner speckle.
+	Denoise Denoise
+}
+
+// DefaultOptions returns the tuning values the package has historically
+// used as fixed constants.
+func DefaultOptions() Options {
+	return Options{
+		GrayDarknessLimit:   grayDarknessLimit,
+		MinHammingDistance:  minDistinctBitsBetweenLines,
+		MaxHammingDistance:  maxDistinctBitsBetweenLines,
+		HighContrastPercent: highContrastPercent,
+		BlackThreshold:      blackHighContrastThreshold,
+		WhiteThreshold:      whiteHighContrastThreshold,
+		MinRetainedFraction: 0,
+	}
+}
+
+// WhitespaceDetector finds borders by scanning inward from each edge for
+// the first pixel at or below Options.GrayDarknessLimit. It is the
+// strategy behind the original Bounds function.
+type WhitespaceDetector struct {
+	Options Options
+}
+
+// DetectBounds implements Detector.
+func (d WhitespaceDetector) DetectBounds(img image.Image) image.Rectangle {
+	left := findBorder(img, image.Pt(1, 0), d.Options)
+	right := findBorder(img, image.Pt(-1, 0), d.Options)
+	top := findBorder(img, image.Pt(0, 1), d.Options)
+	bottom := findBorder(img, image.Pt(0, -1), d.Options)
+
+	bounds := image.Rect(left.X, top.Y, right.X, bottom.Y)
+	return finalizeBounds(img.Bounds(), bounds, d.Options)
+}
+
+// AvgHashDetector finds borders by comparing a rolling average-hash of
+// each line against its neighbours, stopping once consecutive lines stop
+// matching closely enough. It is the strategy behind the original
+// BoundsHash function.
+type AvgHashDetector struct {
+	Options Options
+}
+
+// DetectBounds implements Detector.
+func (d AvgHashDetector) DetectBounds(img image.Image) image.Rectangle {
+	source := img
+	if d.Options.Denoise != DenoiseNone {
+		source = denoise(img, d.Options.Denoise)
+	}
+
+	left := findBorderUsingAvgHash(source, image.Pt(1, 0), d.Options)
+	right := findBorderUsingAvgHash(source, image.Pt(-1, 0), d.Options)
+	top := findBorderUsingAvgHash(source, image.Pt(0, 1), d.Options)
+	bottom := findBorderUsingAvgHash(source, image.Pt(0, -1), d.Options)
+
+	bounds := image.Rect(left.X, top.Y, right.X, bottom.Y)
+	return finalizeBounds(img.Bounds(), bounds, d.Options)
+}
+
+// SauvolaDetector finds borders using local-adaptive Sauvola thresholding.
+// It is the strategy behind BoundsSauvola.
+type SauvolaDetector struct {
+	Options        Options
+	SauvolaOptions SauvolaOptions
+}
+
+// DetectBounds implements Detector.
+func (d SauvolaDetector) DetectBounds(img image.Image) image.Rectangle {
+	bounds := BoundsSauvola(img, d.SauvolaOptions)
+	return finalizeBounds(img.Bounds(), bounds, d.Options)
+}
+
+// finalizeBounds applies Options.Padding to a detected crop and then
+// refuses the crop, falling back to the full image, if it would retain
+// less than Options.MinRetainedFraction of the original area.
+func finalizeBounds(full, detected image.Rectangle, opts Options) image.Rectangle {
+	padded := image.Rect(
+		detected.Min.X-opts.Padding.Left,
+		detected.Min.Y-opts.Padding.Top,
+		detected.Max.X+opts.Padding.Right,
+		detected.Max.Y+opts.Padding.Bottom,
+	).Intersect(full)
+
+	if opts.MinRetainedFraction > 0 {
+		fullArea := full.Dx() * full.Dy()
+		if fullArea > 0 {
+			retained := float64(padded.Dx()*padded.Dy()) / float64(fullArea)
+			if retained < opts.MinRetainedFraction {
+				return full
+			}
+		}
+	}
+
+	return padded
+}
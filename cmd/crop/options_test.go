@@ -0,0 +1,52 @@
+package crop
+
+import (
+	"image"
+	"testing"
+)
+
+func TestFinalizeBoundsAppliesPadding(t *testing.T) {
+	full := image.Rect(0, 0, 100, 100)
+	detected := image.Rect(40, 40, 60, 60)
+	opts := Options{Padding: Padding{Left: 10, Right: 10, Top: 10, Bottom: 10}}
+
+	got := finalizeBounds(full, detected, opts)
+	want := image.Rect(30, 30, 70, 70)
+	if got != want {
+		t.Errorf("finalizeBounds() = %v, want %v", got, want)
+	}
+}
+
+func TestFinalizeBoundsClampsPaddingToFullBounds(t *testing.T) {
+	full := image.Rect(0, 0, 100, 100)
+	detected := image.Rect(5, 5, 20, 20)
+	opts := Options{Padding: Padding{Left: 50, Right: 50, Top: 50, Bottom: 50}}
+
+	got := finalizeBounds(full, detected, opts)
+	want := image.Rect(0, 0, 70, 70)
+	if got != want {
+		t.Errorf("finalizeBounds() = %v, want %v (padding beyond the image edge clamped by Intersect)", got, want)
+	}
+}
+
+func TestFinalizeBoundsRefusesCropUnderMinRetainedFraction(t *testing.T) {
+	full := image.Rect(0, 0, 100, 100)
+	detected := image.Rect(45, 45, 55, 55) // 100 px^2 out of 10000, 1% retained
+	opts := Options{MinRetainedFraction: 0.5}
+
+	got := finalizeBounds(full, detected, opts)
+	if got != full {
+		t.Errorf("finalizeBounds() = %v, want the full bounds %v since the crop falls under MinRetainedFraction", got, full)
+	}
+}
+
+func TestFinalizeBoundsKeepsCropAtOrAboveMinRetainedFraction(t *testing.T) {
+	full := image.Rect(0, 0, 100, 100)
+	detected := image.Rect(45, 45, 55, 55) // exactly 1% retained
+	opts := Options{MinRetainedFraction: 0.01}
+
+	got := finalizeBounds(full, detected, opts)
+	if got != detected {
+		t.Errorf("finalizeBounds() = %v, want the detected crop %v since it meets MinRetainedFraction", got, detected)
+	}
+}
@@ -14,9 +14,33 @@ const blackHighContrastThreshold = 30
 const whiteHighContrastThreshold = 230
 const highContrastPercent = 12
 
+// Mode selects which border detection strategy AutoMode should use.
+type Mode int
+
+const (
+	// ModeAvgHash detects borders by comparing a rolling average-hash of
+	// each line against its neighbours. This is the long-standing default.
+	ModeAvgHash Mode = iota
+	// ModeSauvola detects borders with local-adaptive Sauvola thresholding,
+	// which handles aged paper and uneven lighting better than ModeAvgHash.
+	ModeSauvola
+)
+
 func Auto(img image.Image) (image.Image, error) {
-	bounds := BoundsHash(img)
-	cropped, err := Crop(img, bounds)
+	return AutoMode(img, ModeAvgHash)
+}
+
+// AutoMode crops img using the detector selected by mode.
+func AutoMode(img image.Image, mode Mode) (image.Image, error) {
+	var detector Detector
+	switch mode {
+	case ModeSauvola:
+		detector = SauvolaDetector{Options: DefaultOptions(), SauvolaOptions: DefaultSauvolaOptions()}
+	default:
+		detector = AvgHashDetector{Options: DefaultOptions()}
+	}
+
+	cropped, err := Crop(img, detector.DetectBounds(img))
 	if err != nil {
 		return nil, err
 	}
@@ -36,30 +60,24 @@ func Crop(img image.Image, bounds image.Rectangle) (image.Image, error) {
 	}
 }
 
+// Bounds is a thin wrapper around WhitespaceDetector using DefaultOptions,
+// kept for backward compatibility.
 func Bounds(img image.Image) image.Rectangle {
-	left := findBorder(img, image.Pt(1, 0))
-	right := findBorder(img, image.Pt(-1, 0))
-	top := findBorder(img, image.Pt(0, 1))
-	bottom := findBorder(img, image.Pt(0, -1))
-
-	return image.Rect(left.X, top.Y, right.X, bottom.Y)
+	return WhitespaceDetector{Options: DefaultOptions()}.DetectBounds(img)
 }
 
+// BoundsHash is a thin wrapper around AvgHashDetector using DefaultOptions,
+// kept for backward compatibility.
 func BoundsHash(img image.Image) image.Rectangle {
-	left := findBorderUsingAvgHash(img, image.Pt(1, 0))
-	right := findBorderUsingAvgHash(img, image.Pt(-1, 0))
-	top := findBorderUsingAvgHash(img, image.Pt(0, 1))
-	bottom := findBorderUsingAvgHash(img, image.Pt(0, -1))
-
-	return image.Rect(left.X, top.Y, right.X, bottom.Y)
+	return AvgHashDetector{Options: DefaultOptions()}.DetectBounds(img)
 }
 
-func findBorder(img image.Image, dir image.Point) image.Point {
+func findBorder(img image.Image, dir image.Point, opts Options) image.Point {
 	bounds := img.Bounds()
 	scan := image.Pt(dir.Y, dir.X)
 	dpt := pointInScanCorner(bounds, dir)
 
-	for !scanLineForNonWhitespace(img, dpt, scan) {
+	for !scanLineForNonWhitespace(img, dpt, scan, opts) {
 		dpt = dpt.Add(dir)
 		if !dpt.In(bounds) {
 			dpt = pointInScanCorner(bounds, dir)
@@ -82,10 +100,10 @@ func pointInScanCorner(rect image.Rectangle, dir image.Point) image.Point {
 	}
 }
 
-func scanLineForNonWhitespace(img image.Image, pt image.Point, scan image.Point) bool {
+func scanLineForNonWhitespace(img image.Image, pt image.Point, scan image.Point, opts Options) bool {
 	for spt := pt; spt.In(img.Bounds()); spt = spt.Add(scan) {
 		if gray, ok := color.GrayModel.Convert(img.At(spt.X, spt.Y)).(color.Gray); ok {
-			if gray.Y <= grayDarknessLimit {
+			if gray.Y <= opts.GrayDarknessLimit {
 				return true
 			}
 		}
@@ -94,17 +112,17 @@ func scanLineForNonWhitespace(img image.Image, pt image.Point, scan image.Point)
 	return false
 }
 
-func findBorderUsingAvgHash(img image.Image, dir image.Point) image.Point {
+func findBorderUsingAvgHash(img image.Image, dir image.Point, opts Options) image.Point {
 	bounds := img.Bounds()
 	scan := image.Pt(dir.Y, dir.X)
 	dpt := pointInScanCorner(bounds, dir)
 
-	prevAvg, prevHighContrast := lineAverageHash(img, dpt, scan)
+	prevAvg, prevHighContrast := lineAverageHash(img, dpt, scan, opts)
 	dpt = dpt.Add(dir)
 
 	for {
-		avgHash, highContrastHash := lineAverageHash(img, dpt, scan)
-		if !hashesMatch(prevAvg, prevHighContrast, avgHash, highContrastHash) {
+		avgHash, highContrastHash := lineAverageHash(img, dpt, scan, opts)
+		if !hashesMatch(prevAvg, prevHighContrast, avgHash, highContrastHash, opts) {
 			break
 		}
 		prevAvg, prevHighContrast = avgHash, highContrastHash
@@ -122,19 +140,19 @@ func findBorderUsingAvgHash(img image.Image, dir image.Point) image.Point {
 	}
 }
 
-func hashesMatch(prevAvg uint32, prevHighContrast uint32, avgHash uint32, highContrastHash uint32) bool {
+func hashesMatch(prevAvg uint32, prevHighContrast uint32, avgHash uint32, highContrastHash uint32, opts Options) bool {
 	// If prev avg was white or black for the full line, use a lower, more sensitive value
 	if (prevAvg^0xFFFFFFFF == 0 || prevAvg^0 == 0) &&
-		bits.OnesCount32(avgHash^prevAvg)+bits.OnesCount32(highContrastHash^prevHighContrast) >= minDistinctBitsBetweenLines {
+		bits.OnesCount32(avgHash^prevAvg)+bits.OnesCount32(highContrastHash^prevHighContrast) >= opts.MinHammingDistance {
 		return false
 	}
-	if bits.OnesCount32(avgHash^prevAvg)+bits.OnesCount32(highContrastHash^prevHighContrast) >= maxDistinctBitsBetweenLines {
+	if bits.OnesCount32(avgHash^prevAvg)+bits.OnesCount32(highContrastHash^prevHighContrast) >= opts.MaxHammingDistance {
 		return false
 	}
 	return true
 }
 
-func lineAverageHash(img image.Image, pt image.Point, scan image.Point) (avgHash uint32, highContrastHash uint32) {
+func lineAverageHash(img image.Image, pt image.Point, scan image.Point, opts Options) (avgHash uint32, highContrastHash uint32) {
 	length := 0
 	if scan.X != 0 {
 		length = img.Bounds().Max.X
@@ -149,23 +167,23 @@ func lineAverageHash(img image.Image, pt image.Point, scan image.Point) (avgHash
 		if gray, ok := color.GrayModel.Convert(img.At(spt.X, spt.Y)).(color.Gray); ok {
 			if i%windowSize == windowSize-1 {
 				// check if average is "white" and set bit in hash, just before going into next block window.
-				if partialSum > uint32(windowSize)*grayDarknessLimit {
+				if partialSum > uint32(windowSize)*uint32(opts.GrayDarknessLimit) {
 					avgHash = setBit32(avgHash, i/windowSize)
-					if lows > float64(windowSize)*highContrastPercent/100 {
+					if lows > float64(windowSize)*opts.HighContrastPercent/100 {
 						highContrastHash = setBit32(highContrastHash, i/windowSize)
 					}
 				} else {
-					if highs > float64(windowSize)*highContrastPercent/100 {
+					if highs > float64(windowSize)*opts.HighContrastPercent/100 {
 						highContrastHash = setBit32(highContrastHash, i/windowSize)
 					}
 				}
 				partialSum, lows, highs = 0, 0, 0
 			}
 			partialSum += uint32(gray.Y)
-			if gray.Y > whiteHighContrastThreshold {
+			if gray.Y > opts.WhiteThreshold {
 				highs++
 			}
-			if gray.Y < blackHighContrastThreshold {
+			if gray.Y < opts.BlackThreshold {
 				lows++
 			}
 			i++
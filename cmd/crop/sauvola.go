@@ -0,0 +1,177 @@
+package crop
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// SauvolaOptions configures BoundsSauvola's local-adaptive thresholding.
+type SauvolaOptions struct {
+	// WindowSize is the side length, in pixels, of the square window used
+	// to compute the local mean and standard deviation around each pixel.
+	WindowSize int
+	// K is the Sauvola sensitivity parameter; higher values require more
+	// local contrast before a pixel is considered foreground.
+	K float64
+	// R is the dynamic range of the standard deviation, typically 128 for
+	// 8-bit grayscale images.
+	R float64
+	// EmptyLinePercent is the maximum percentage of foreground pixels a
+	// row or column may have and still be treated as an empty border line.
+	EmptyLinePercent float64
+}
+
+// DefaultSauvolaOptions returns the options used if none are supplied.
+func DefaultSauvolaOptions() SauvolaOptions {
+	return SauvolaOptions{
+		WindowSize:       31,
+		K:                0.34,
+		R:                128,
+		EmptyLinePercent: 0.5,
+	}
+}
+
+// BoundsSauvola detects page borders using Sauvola adaptive thresholding,
+// which tracks local brightness and contrast instead of a single global
+// cutoff. It copes much better than BoundsHash with yellowed paper, inky
+// backgrounds, and pages with gradient lighting.
+func BoundsSauvola(img image.Image, opts SauvolaOptions) image.Rectangle {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 {
+		return bounds
+	}
+
+	fg := sauvolaForeground(img, opts)
+
+	left := sauvolaEdge(fg, w, h, image.Pt(1, 0), opts.EmptyLinePercent)
+	right := sauvolaEdge(fg, w, h, image.Pt(-1, 0), opts.EmptyLinePercent)
+	top := sauvolaEdge(fg, w, h, image.Pt(0, 1), opts.EmptyLinePercent)
+	bottom := sauvolaEdge(fg, w, h, image.Pt(0, -1), opts.EmptyLinePercent)
+
+	return image.Rect(left+bounds.Min.X, top+bounds.Min.Y, right+bounds.Min.X, bottom+bounds.Min.Y)
+}
+
+// sauvolaForeground builds integral images of the grayscale values and of
+// their squares, then uses them to classify every pixel as foreground or
+// background in O(1) per pixel, for an overall O(W*H) pass.
+func sauvolaForeground(img image.Image, opts SauvolaOptions) []bool {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	sum := make([]int64, (w+1)*(h+1))
+	sumSq := make([]int64, (w+1)*(h+1))
+	gray := make([]uint8, w*h)
+	stride := w + 1
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.GrayModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.Gray)
+			gray[y*w+x] = c.Y
+			v := int64(c.Y)
+			sum[(y+1)*stride+(x+1)] = v + sum[y*stride+(x+1)] + sum[(y+1)*stride+x] - sum[y*stride+x]
+			sumSq[(y+1)*stride+(x+1)] = v*v + sumSq[y*stride+(x+1)] + sumSq[(y+1)*stride+x] - sumSq[y*stride+x]
+		}
+	}
+
+	windowSize := opts.WindowSize
+	if windowSize < 1 {
+		windowSize = 1
+	}
+	half := windowSize / 2
+
+	fg := make([]bool, w*h)
+	for y := 0; y < h; y++ {
+		y0, y1 := y-half, y+half
+		if y0 < 0 {
+			y0 = 0
+		}
+		if y1 >= h {
+			y1 = h - 1
+		}
+		for x := 0; x < w; x++ {
+			x0, x1 := x-half, x+half
+			if x0 < 0 {
+				x0 = 0
+			}
+			if x1 >= w {
+				x1 = w - 1
+			}
+
+			count := int64(x1-x0+1) * int64(y1-y0+1)
+			s := sum[(y1+1)*stride+(x1+1)] - sum[y0*stride+(x1+1)] - sum[(y1+1)*stride+x0] + sum[y0*stride+x0]
+			sq := sumSq[(y1+1)*stride+(x1+1)] - sumSq[y0*stride+(x1+1)] - sumSq[(y1+1)*stride+x0] + sumSq[y0*stride+x0]
+
+			mean := float64(s) / float64(count)
+			variance := float64(sq)/float64(count) - mean*mean
+			if variance < 0 {
+				variance = 0
+			}
+			stddev := math.Sqrt(variance)
+
+			threshold := mean * (1 + opts.K*(stddev/opts.R-1))
+			fg[y*w+x] = float64(gray[y*w+x]) < threshold
+		}
+	}
+
+	return fg
+}
+
+// sauvolaEdge scans inward from the edge of the foreground grid indicated
+// by dir for the first row or column whose share of foreground pixels
+// exceeds emptyLinePercent. Scanning from the left or top it returns that
+// line's coordinate; scanning from the right or bottom it returns one past
+// it, so the result is an exclusive bound. If no line qualifies, it returns
+// the edge the scan started from (0, or w/h).
+func sauvolaEdge(fg []bool, w, h int, dir image.Point, emptyLinePercent float64) int {
+	if dir.X != 0 {
+		start, end, step := 0, w, 1
+		if dir.X < 0 {
+			start, end, step = w-1, -1, -1
+		}
+		limit := float64(h) * emptyLinePercent / 100
+		for x := start; x != end; x += step {
+			count := 0
+			for y := 0; y < h; y++ {
+				if fg[y*w+x] {
+					count++
+				}
+			}
+			if float64(count) > limit {
+				if dir.X < 0 {
+					return x + 1
+				}
+				return x
+			}
+		}
+		if dir.X < 0 {
+			return w
+		}
+		return start
+	}
+
+	start, end, step := 0, h, 1
+	if dir.Y < 0 {
+		start, end, step = h-1, -1, -1
+	}
+	limit := float64(w) * emptyLinePercent / 100
+	for y := start; y != end; y += step {
+		count := 0
+		for x := 0; x < w; x++ {
+			if fg[y*w+x] {
+				count++
+			}
+		}
+		if float64(count) > limit {
+			if dir.Y < 0 {
+				return y + 1
+			}
+			return y
+		}
+	}
+	if dir.Y < 0 {
+		return h
+	}
+	return start
+}
@@ -0,0 +1,44 @@
+package crop
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestBoundsSauvolaBlankPageReturnsFullBounds(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 10, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			img.SetGray(x, y, color.Gray{Y: 255})
+		}
+	}
+
+	got := BoundsSauvola(img, DefaultSauvolaOptions())
+	want := image.Rect(0, 0, 10, 10)
+	if got != want {
+		t.Errorf("BoundsSauvola(blank) = %v, want %v", got, want)
+	}
+}
+
+func TestBoundsSauvolaFindsDarkContent(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 40, 40))
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 40; x++ {
+			img.SetGray(x, y, color.Gray{Y: 255})
+		}
+	}
+	for y := 10; y < 30; y++ {
+		for x := 10; x < 30; x++ {
+			img.SetGray(x, y, color.Gray{Y: 0})
+		}
+	}
+
+	got := BoundsSauvola(img, DefaultSauvolaOptions())
+	if got.Min.X < 1 || got.Min.Y < 1 || got.Max.X > 39 || got.Max.Y > 39 {
+		t.Errorf("BoundsSauvola(bordered square) = %v, want a crop tighter than the full 40x40 bounds", got)
+	}
+	if got.Min.X > 10 || got.Min.Y > 10 || got.Max.X < 30 || got.Max.Y < 30 {
+		t.Errorf("BoundsSauvola(bordered square) = %v, want it to retain the dark square at (10,10)-(30,30)", got)
+	}
+}